@@ -0,0 +1,104 @@
+package vlog
+
+import (
+	"context"
+	"sync"
+)
+
+type contextKey struct{}
+
+var loggerContextKey = contextKey{}
+
+// NewContext returns a copy of ctx carrying l, retrievable with
+// FromContext.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// FromContext returns the Logger stored in ctx by NewContext, or the root
+// logger if ctx carries none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerContextKey).(*Logger); ok {
+		return l
+	}
+	return GetLogger("", nil)
+}
+
+// ContextExtractor pulls structured fields out of a context.Context to be
+// attached to every record logged through the *Ctx methods, e.g. request
+// IDs, or trace/span IDs threaded via your tracing library's own context
+// keys. vlog has no tracing dependency of its own: register an extractor
+// for whatever you use, e.g. for OpenTelemetry:
+//
+//	vlog.RegisterContextExtractor(func(ctx context.Context) []any {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return nil
+//		}
+//		return []any{"trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()}
+//	})
+type ContextExtractor func(context.Context) []any
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []ContextExtractor
+)
+
+// RegisterContextExtractor adds fn to the set of extractors consulted by
+// the *Ctx logging methods. Extractors run in registration order and
+// their results are appended in that order.
+func RegisterContextExtractor(fn ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// contextFields collects the correlation fields for ctx by running every
+// registered ContextExtractor against it.
+func contextFields(ctx context.Context) []any {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	var fields []any
+	for _, extract := range extractors {
+		fields = append(fields, extract(ctx)...)
+	}
+
+	return fields
+}
+
+// TraceCtx emits a structured log at trace logging level, with
+// correlation fields extracted from ctx prepended to kv.
+func (l *Logger) TraceCtx(ctx context.Context, msg string, kv ...any) {
+	l.Trace(msg, append(contextFields(ctx), kv...)...)
+}
+
+// DebugCtx emits a structured log at debug logging level, with
+// correlation fields extracted from ctx prepended to kv.
+func (l *Logger) DebugCtx(ctx context.Context, msg string, kv ...any) {
+	l.Debug(msg, append(contextFields(ctx), kv...)...)
+}
+
+// InfoCtx emits a structured log at info logging level, with correlation
+// fields extracted from ctx prepended to kv.
+func (l *Logger) InfoCtx(ctx context.Context, msg string, kv ...any) {
+	l.Info(msg, append(contextFields(ctx), kv...)...)
+}
+
+// WarnCtx emits a structured log at warn logging level, with correlation
+// fields extracted from ctx prepended to kv.
+func (l *Logger) WarnCtx(ctx context.Context, msg string, kv ...any) {
+	l.Warn(msg, append(contextFields(ctx), kv...)...)
+}
+
+// ErrorCtx emits a structured log at error logging level, with
+// correlation fields extracted from ctx prepended to kv.
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, kv ...any) {
+	l.Error(msg, append(contextFields(ctx), kv...)...)
+}
+
+// CriticalCtx emits a structured log at critical logging level, with
+// correlation fields extracted from ctx prepended to kv.
+func (l *Logger) CriticalCtx(ctx context.Context, msg string, kv ...any) {
+	l.Critical(msg, append(contextFields(ctx), kv...)...)
+}
@@ -0,0 +1,36 @@
+package vlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func TestContextExtractorFieldsAreLogged(t *testing.T) {
+	RegisterContextExtractor(func(ctx context.Context) []any {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return []any{"request_id", id}
+		}
+		return nil
+	})
+
+	buf := new(bytes.Buffer)
+	lg := GetLogger("context-test-logger", nil)
+	lg.SetOutput(buf)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc123")
+	lg.InfoCtx(ctx, "handled request")
+
+	if got := buf.String(); !strings.Contains(got, "request_id=abc123") {
+		t.Fatalf("InfoCtx output %q missing extracted field", got)
+	}
+}
+
+func TestFromContextFallsBackToRootLogger(t *testing.T) {
+	if got := FromContext(context.Background()); got != GetLogger("", nil) {
+		t.Fatalf("FromContext on bare context = %v, want root logger", got)
+	}
+}
@@ -0,0 +1,238 @@
+package vlog
+
+import (
+	"context"
+	"fmt"
+)
+
+// redacted is substituted for any structured field value that FilterKey or
+// FilterValue matches.
+const redacted = "***"
+
+// Filter wraps a Logger and applies redaction and drop rules to every
+// record before it reaches the wrapped logger. It exposes the same
+// level-based logging methods as Logger so it can be used as a drop-in
+// front end.
+type Filter struct {
+	next *Logger
+
+	level  Level
+	keys   map[string]struct{}
+	values map[string]struct{}
+	fn     func(Level, ...any) bool
+}
+
+// FilterOption configures a Filter constructed with NewFilter.
+type FilterOption func(*Filter)
+
+// FilterLevel drops records below lvl. The default is TRACE, i.e. no
+// level-based dropping.
+func FilterLevel(lvl Level) FilterOption {
+	return func(f *Filter) { f.level = lvl }
+}
+
+// FilterKey redacts the value of any structured field whose key is one of
+// keys, replacing it with "***".
+func FilterKey(keys ...string) FilterOption {
+	return func(f *Filter) {
+		if f.keys == nil {
+			f.keys = make(map[string]struct{}, len(keys))
+		}
+		for _, k := range keys {
+			f.keys[k] = struct{}{}
+		}
+	}
+}
+
+// FilterValue redacts any structured field whose value equals one of
+// values, replacing it with "***".
+func FilterValue(values ...string) FilterOption {
+	return func(f *Filter) {
+		if f.values == nil {
+			f.values = make(map[string]struct{}, len(values))
+		}
+		for _, v := range values {
+			f.values[v] = struct{}{}
+		}
+	}
+}
+
+// FilterFunc drops a record entirely when fn returns true. fn receives the
+// record's level followed by its message and structured fields.
+func FilterFunc(fn func(Level, ...any) bool) FilterOption {
+	return func(f *Filter) { f.fn = fn }
+}
+
+// NewFilter returns a Filter that applies opts to every record before
+// passing it on to next.
+func NewFilter(next *Logger, opts ...FilterOption) *Filter {
+	f := &Filter{next: next, level: TRACE}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// redact replaces the value of any kv pair whose key or value matches the
+// filter's configured keys/values.
+func (f *Filter) redact(kv []any) []any {
+	if len(f.keys) == 0 && len(f.values) == 0 {
+		return kv
+	}
+
+	out := append([]any(nil), kv...)
+	for i := 0; i+1 < len(out); i += 2 {
+		key := fmt.Sprint(out[i])
+		val := fmt.Sprint(out[i+1])
+
+		if _, ok := f.keys[key]; ok {
+			out[i+1] = redacted
+			continue
+		}
+		if _, ok := f.values[val]; ok {
+			out[i+1] = redacted
+		}
+	}
+
+	return out
+}
+
+// dropped reports whether the record should be suppressed entirely.
+func (f *Filter) dropped(level Level, msg string, kv []any) bool {
+	if level < f.level {
+		return true
+	}
+	if f.fn == nil {
+		return false
+	}
+	return f.fn(level, append([]any{msg}, kv...)...)
+}
+
+func (f *Filter) log(level Level, msg string, kv []any) {
+	if f.dropped(level, msg, kv) {
+		return
+	}
+
+	kv = f.redact(kv)
+
+	switch level {
+	case TRACE:
+		f.next.Trace(msg, kv...)
+	case DEBUG:
+		f.next.Debug(msg, kv...)
+	case INFO:
+		f.next.Info(msg, kv...)
+	case WARN:
+		f.next.Warn(msg, kv...)
+	case ERROR:
+		f.next.Error(msg, kv...)
+	case CRITICAL:
+		f.next.Critical(msg, kv...)
+	}
+}
+
+// Trace emits a structured log at trace logging level, unless dropped or
+// redacted by the filter.
+func (f *Filter) Trace(msg string, kv ...any) { f.log(TRACE, msg, kv) }
+
+// Debug emits a structured log at debug logging level, unless dropped or
+// redacted by the filter.
+func (f *Filter) Debug(msg string, kv ...any) { f.log(DEBUG, msg, kv) }
+
+// Info emits a structured log at info logging level, unless dropped or
+// redacted by the filter.
+func (f *Filter) Info(msg string, kv ...any) { f.log(INFO, msg, kv) }
+
+// Warn emits a structured log at warn logging level, unless dropped or
+// redacted by the filter.
+func (f *Filter) Warn(msg string, kv ...any) { f.log(WARN, msg, kv) }
+
+// Error emits a structured log at error logging level, unless dropped or
+// redacted by the filter.
+func (f *Filter) Error(msg string, kv ...any) { f.log(ERROR, msg, kv) }
+
+// Critical emits a structured log at critical logging level, unless
+// dropped or redacted by the filter.
+func (f *Filter) Critical(msg string, kv ...any) { f.log(CRITICAL, msg, kv) }
+
+// Tracef emits a log at trace logging level with specified format string,
+// unless dropped by the filter.
+func (f *Filter) Tracef(format string, v ...interface{}) {
+	f.log(TRACE, fmt.Sprintf(format, v...), nil)
+}
+
+// Debugf emits a log at debug logging level with specified format string,
+// unless dropped by the filter.
+func (f *Filter) Debugf(format string, v ...interface{}) {
+	f.log(DEBUG, fmt.Sprintf(format, v...), nil)
+}
+
+// Infof emits a log at info logging level with specified format string,
+// unless dropped by the filter.
+func (f *Filter) Infof(format string, v ...interface{}) { f.log(INFO, fmt.Sprintf(format, v...), nil) }
+
+// Warnf emits a log at warn logging level with specified format string,
+// unless dropped by the filter.
+func (f *Filter) Warnf(format string, v ...interface{}) { f.log(WARN, fmt.Sprintf(format, v...), nil) }
+
+// Errorf emits a log at error logging level with specified format string,
+// unless dropped by the filter.
+func (f *Filter) Errorf(format string, v ...interface{}) {
+	f.log(ERROR, fmt.Sprintf(format, v...), nil)
+}
+
+// Criticalf emits a log at critical logging level with specified format
+// string, unless dropped by the filter.
+func (f *Filter) Criticalf(format string, v ...interface{}) {
+	f.log(CRITICAL, fmt.Sprintf(format, v...), nil)
+}
+
+// logCtx prepends ctx's correlation fields to kv before running it through
+// the same drop/redact path as log, so a Filter participates in
+// context/trace correlation (see RegisterContextExtractor) exactly like a
+// plain *Logger does.
+func (f *Filter) logCtx(ctx context.Context, level Level, msg string, kv []any) {
+	f.log(level, msg, append(contextFields(ctx), kv...))
+}
+
+// TraceCtx emits a structured log at trace logging level, with
+// correlation fields extracted from ctx prepended to kv, unless dropped
+// or redacted by the filter.
+func (f *Filter) TraceCtx(ctx context.Context, msg string, kv ...any) {
+	f.logCtx(ctx, TRACE, msg, kv)
+}
+
+// DebugCtx emits a structured log at debug logging level, with
+// correlation fields extracted from ctx prepended to kv, unless dropped
+// or redacted by the filter.
+func (f *Filter) DebugCtx(ctx context.Context, msg string, kv ...any) {
+	f.logCtx(ctx, DEBUG, msg, kv)
+}
+
+// InfoCtx emits a structured log at info logging level, with correlation
+// fields extracted from ctx prepended to kv, unless dropped or redacted
+// by the filter.
+func (f *Filter) InfoCtx(ctx context.Context, msg string, kv ...any) {
+	f.logCtx(ctx, INFO, msg, kv)
+}
+
+// WarnCtx emits a structured log at warn logging level, with correlation
+// fields extracted from ctx prepended to kv, unless dropped or redacted
+// by the filter.
+func (f *Filter) WarnCtx(ctx context.Context, msg string, kv ...any) {
+	f.logCtx(ctx, WARN, msg, kv)
+}
+
+// ErrorCtx emits a structured log at error logging level, with
+// correlation fields extracted from ctx prepended to kv, unless dropped
+// or redacted by the filter.
+func (f *Filter) ErrorCtx(ctx context.Context, msg string, kv ...any) {
+	f.logCtx(ctx, ERROR, msg, kv)
+}
+
+// CriticalCtx emits a structured log at critical logging level, with
+// correlation fields extracted from ctx prepended to kv, unless dropped
+// or redacted by the filter.
+func (f *Filter) CriticalCtx(ctx context.Context, msg string, kv ...any) {
+	f.logCtx(ctx, CRITICAL, msg, kv)
+}
@@ -0,0 +1,67 @@
+package vlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFilterRedactsKeyAndValue(t *testing.T) {
+	buf := new(bytes.Buffer)
+	lg := GetLogger("filter-test-logger", nil)
+	lg.SetOutput(buf)
+
+	f := NewFilter(lg, FilterKey("password"), FilterValue("secret-token"))
+	f.Info("login", "password", "hunter2", "session", "secret-token")
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "secret-token") {
+		t.Fatalf("Filter output %q leaked a value that should have been redacted", out)
+	}
+	if !strings.Contains(out, "password=***") || !strings.Contains(out, "session=***") {
+		t.Fatalf("Filter output %q missing expected redaction markers", out)
+	}
+}
+
+func TestFilterFuncDropsRecord(t *testing.T) {
+	buf := new(bytes.Buffer)
+	lg := GetLogger("filter-drop-test-logger", nil)
+	lg.SetOutput(buf)
+
+	f := NewFilter(lg, FilterFunc(func(level Level, v ...any) bool {
+		return level == DEBUG
+	}))
+
+	f.Debug("should be dropped")
+	f.Info("should pass through")
+
+	out := buf.String()
+	if strings.Contains(out, "should be dropped") {
+		t.Fatalf("Filter output %q contains a record FilterFunc should have dropped", out)
+	}
+	if !strings.Contains(out, "should pass through") {
+		t.Fatalf("Filter output %q missing record that should have passed", out)
+	}
+}
+
+func TestFilterCtxIncludesContextFields(t *testing.T) {
+	RegisterContextExtractor(func(ctx context.Context) []any {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return []any{"request_id", id}
+		}
+		return nil
+	})
+
+	buf := new(bytes.Buffer)
+	lg := GetLogger("filter-ctx-test-logger", nil)
+	lg.SetOutput(buf)
+
+	f := NewFilter(lg)
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "xyz789")
+	f.InfoCtx(ctx, "handled request")
+
+	if got := buf.String(); !strings.Contains(got, "request_id=xyz789") {
+		t.Fatalf("Filter.InfoCtx output %q missing extracted field", got)
+	}
+}
@@ -0,0 +1,191 @@
+package vlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String returns the human-readable name of the level, e.g. "info".
+func (lvl Level) String() string {
+	switch lvl {
+	case TRACE:
+		return "trace"
+	case DEBUG:
+		return "debug"
+	case INFO:
+		return "info"
+	case WARN:
+		return "warn"
+	case ERROR:
+		return "error"
+	case CRITICAL:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Formatter renders a single log record into its wire representation.
+// kv holds alternating key/value pairs already merged from the logger's
+// accumulated context (via With) and the call-site fields.
+type Formatter interface {
+	Format(level Level, ts time.Time, name, msg string, kv []any) []byte
+}
+
+// TextFormatter renders records in vlog's original human-readable form:
+// "<ts> <name> <level> <msg> key=value ...".
+type TextFormatter struct{}
+
+func (TextFormatter) Format(level Level, ts time.Time, name, msg string, kv []any) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(ts.Format("2006/01/02 15:04:05.000000"))
+	buf.WriteByte(' ')
+	if name != "" {
+		buf.WriteString(name)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(msg)
+	writeKVPairs(&buf, kv, formatLogfmtValue)
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
+
+// LogfmtFormatter renders records as logfmt (key=value), the format used by
+// tools such as heroku/logfmt and consumed by most log aggregators.
+type LogfmtFormatter struct{}
+
+func (LogfmtFormatter) Format(level Level, ts time.Time, name, msg string, kv []any) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("ts=")
+	buf.WriteString(ts.Format(time.RFC3339Nano))
+	buf.WriteString(" level=")
+	buf.WriteString(level.String())
+	if name != "" {
+		buf.WriteString(" logger=")
+		buf.WriteString(formatLogfmtValue(name))
+	}
+	buf.WriteString(" msg=")
+	buf.WriteString(formatLogfmtValue(msg))
+	writeKVPairs(&buf, kv, formatLogfmtValue)
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
+
+// formatLogfmtValue quotes a value if it contains spaces, '=' or quotes.
+func formatLogfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " =\"") {
+		return fmt.Sprintf("%q", v)
+	}
+	return v
+}
+
+// writeKVPairs appends " key=value" for each pair in kv, using quote to
+// render the value. Odd trailing keys are paired with a "MISSING" value.
+func writeKVPairs(buf *bytes.Buffer, kv []any, quote func(string) string) {
+	for i := 0; i < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+
+		var val string
+		if i+1 < len(kv) {
+			val = fmt.Sprint(kv[i+1])
+		} else {
+			val = "MISSING"
+		}
+
+		buf.WriteByte(' ')
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		buf.WriteString(quote(val))
+	}
+}
+
+// JSONFormatter renders records as single-line JSON objects with "ts",
+// "level", "logger" and "msg" fields plus the structured fields flattened
+// alongside them.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(level Level, ts time.Time, name, msg string, kv []any) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	writeJSONField(&buf, true, "ts", ts.Format(time.RFC3339Nano))
+	writeJSONField(&buf, false, "level", level.String())
+	if name != "" {
+		writeJSONField(&buf, false, "logger", name)
+	}
+	writeJSONField(&buf, false, "msg", msg)
+
+	for i := 0; i < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+
+		var val any
+		if i+1 < len(kv) {
+			val = kv[i+1]
+		} else {
+			val = "MISSING"
+		}
+
+		buf.WriteByte(',')
+		writeJSONString(&buf, key)
+		buf.WriteByte(':')
+		writeJSONValue(&buf, val)
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+func writeJSONField(buf *bytes.Buffer, first bool, key, val string) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	writeJSONString(buf, key)
+	buf.WriteByte(':')
+	writeJSONString(buf, val)
+}
+
+func writeJSONValue(buf *bytes.Buffer, v any) {
+	switch val := v.(type) {
+	case string:
+		writeJSONString(buf, val)
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case int:
+		buf.WriteString(strconv.Itoa(val))
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+		buf.WriteString(fmt.Sprintf("%d", val))
+	case float32:
+		buf.WriteString(strconv.FormatFloat(float64(val), 'g', -1, 32))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(val, 'g', -1, 64))
+	case nil:
+		buf.WriteString("null")
+	case error:
+		writeJSONString(buf, val.Error())
+	case fmt.Stringer:
+		writeJSONString(buf, val.String())
+	default:
+		writeJSONString(buf, fmt.Sprint(val))
+	}
+}
+
+// writeJSONString appends s to buf as a properly escaped JSON string.
+// fmt.Sprintf("%q", s) is NOT a substitute here: it follows Go string
+// escaping (e.g. "\x00", "\a", "\v"), not JSON's, which only allows
+// "\uXXXX" for control codes and would produce invalid JSON for raw
+// control bytes in a message or field (e.g. logged binary payloads).
+func writeJSONString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s) // json.Marshal never errors on a string
+	buf.Write(b)
+}
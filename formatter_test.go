@@ -0,0 +1,64 @@
+package vlog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatterPreservesFieldTypes(t *testing.T) {
+	ts := time.Unix(0, 0)
+	out := string(JSONFormatter{}.Format(INFO, ts, "svc", "hit", []any{"count", 5, "ok", true, "ratio", 1.5}))
+
+	for _, want := range []string{`"count":5`, `"ok":true`, `"ratio":1.5`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("JSONFormatter output %q missing %q", out, want)
+		}
+	}
+
+	if strings.Contains(out, `"count":"5"`) || strings.Contains(out, `"ok":"true"`) {
+		t.Fatalf("JSONFormatter output %q stringified a numeric/bool field", out)
+	}
+}
+
+func TestLogfmtFormatterQuotesValuesWithSpaces(t *testing.T) {
+	ts := time.Unix(0, 0)
+	out := string(LogfmtFormatter{}.Format(WARN, ts, "svc", "hello world", []any{"path", "has spaces"}))
+
+	if !strings.Contains(out, `msg="hello world"`) {
+		t.Fatalf("LogfmtFormatter output %q did not quote msg with spaces", out)
+	}
+	if !strings.Contains(out, `path="has spaces"`) {
+		t.Fatalf("LogfmtFormatter output %q did not quote value with spaces", out)
+	}
+}
+
+func TestJSONFormatterEscapesControlBytes(t *testing.T) {
+	ts := time.Unix(0, 0)
+	out := JSONFormatter{}.Format(INFO, ts, "svc", "bell\a vtab\v null\x00", []any{"raw", "\x01\x1f"})
+
+	if !json.Valid(out) {
+		t.Fatalf("JSONFormatter output is not valid JSON: %s", out)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %v, output: %s", err, out)
+	}
+	if decoded["msg"] != "bell\a vtab\v null\x00" {
+		t.Fatalf("decoded msg = %q, want the original control bytes preserved", decoded["msg"])
+	}
+	if decoded["raw"] != "\x01\x1f" {
+		t.Fatalf("decoded raw = %q, want the original control bytes preserved", decoded["raw"])
+	}
+}
+
+func TestTextFormatterIncludesLevelAndMessage(t *testing.T) {
+	ts := time.Unix(0, 0)
+	out := string(TextFormatter{}.Format(ERROR, ts, "svc", "boom", nil))
+
+	if !strings.Contains(out, "svc") || !strings.Contains(out, "error") || !strings.Contains(out, "boom") {
+		t.Fatalf("TextFormatter output %q missing expected fields", out)
+	}
+}
@@ -0,0 +1,180 @@
+package vlog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// explicitLevels holds the level explicitly configured for a logger name,
+// via SetLevel or SetLevels. Reads and writes are guarded by mu, the same
+// mutex that protects the loggers registry. Names not present here inherit
+// their effective level from the nearest configured ancestor in the
+// dotted-name hierarchy, e.g. "app.db.pool" inherits from "app.db" then
+// "app" then the root logger ("").
+var explicitLevels = map[string]Level{"": TRACE}
+
+// effectiveLevelLocked resolves name's effective level by walking up the
+// dotted-name hierarchy. Callers must hold mu.
+func effectiveLevelLocked(name string) Level {
+	for {
+		if lvl, ok := explicitLevels[name]; ok {
+			return lvl
+		}
+
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			name = ""
+			continue
+		}
+		name = name[:idx]
+	}
+}
+
+// ParseLevel parses the case-insensitive level name used in SetLevels
+// specs and flag values, e.g. "info" or "INFO".
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "TRACE":
+		return TRACE, nil
+	case "DEBUG":
+		return DEBUG, nil
+	case "INFO":
+		return INFO, nil
+	case "WARN":
+		return WARN, nil
+	case "ERROR":
+		return ERROR, nil
+	case "CRITICAL":
+		return CRITICAL, nil
+	default:
+		return 0, fmt.Errorf("vlog: unknown level %q", s)
+	}
+}
+
+// SetLevels configures the effective level of one or more loggers by name
+// from a capnslog/glog-style spec: a comma-separated list of
+// "name=LEVEL" pairs, e.g. "app=INFO,app.db=DEBUG,app.db.pool=TRACE".
+// A name that has no logger yet takes effect as soon as one is created
+// under it (see GetLogger, MustGetPackageLogger). SetLevels also reads
+// VLOG_LEVELS at package init, so programs get hierarchical level
+// configuration for free from the environment.
+//
+// The whole spec is validated before any of it is applied: a malformed
+// entry anywhere in spec leaves every name's level unchanged and returns
+// an error, rather than partially applying the entries that parsed before
+// the bad one.
+func SetLevels(spec string) error {
+	type entry struct {
+		name string
+		lvl  Level
+	}
+
+	var entries []entry
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, levelStr, found := strings.Cut(part, "=")
+		if !found {
+			return fmt.Errorf("vlog: invalid level spec %q, want name=LEVEL", part)
+		}
+
+		lvl, err := ParseLevel(levelStr)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{name, lvl})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, e := range entries {
+		explicitLevels[e.name] = e.lvl
+		if logger, ok := loggers[e.name]; ok {
+			logger.level = e.lvl
+			logger.levelSet = true
+		}
+	}
+
+	return nil
+}
+
+// LevelsFlag adapts SetLevels to flag.Value, so programs can bind it with
+// flag.Var(&vlog.LevelsFlag, "vlog-levels", "...").
+var LevelsFlag levelsFlag
+
+type levelsFlag struct{}
+
+// String returns the currently configured levels as a SetLevels spec.
+func (levelsFlag) String() string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if explicitLevels == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(explicitLevels))
+	for name, lvl := range explicitLevels {
+		if name == "" {
+			continue
+		}
+		parts = append(parts, name+"="+lvl.String())
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Set parses value as a SetLevels spec and applies it.
+func (levelsFlag) Set(value string) error {
+	return SetLevels(value)
+}
+
+// MustGetPackageLogger returns the package-scoped Logger for the caller's
+// own package, deriving its dotted name from the caller's import path via
+// runtime.Caller. It panics if the caller's package cannot be determined,
+// which should only happen if the calling goroutine's stack is corrupted.
+func MustGetPackageLogger() *Logger {
+	pkg := callerPackage(2)
+	if pkg == "" {
+		panic("vlog: MustGetPackageLogger: unable to determine caller's package")
+	}
+	return GetLogger(pkg, nil)
+}
+
+// callerPackage returns the import path of the package skip frames up the
+// call stack, derived from the calling function's fully-qualified name.
+//
+// Known limitation: it truncates at the first "." after the last "/", so
+// an import path whose last segment itself contains a dot (e.g.
+// "gopkg.in/yaml.v2") is mis-derived (yields "gopkg.in/yaml" instead of
+// "gopkg.in/yaml.v2"). Go's runtime does not expose the package/identifier
+// boundary any more precisely than this, so callers with dotted-segment
+// import paths should name their package logger explicitly via GetLogger
+// instead of relying on MustGetPackageLogger.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	full := fn.Name()
+
+	slash := strings.LastIndex(full, "/")
+	rest := full[slash+1:]
+	if dot := strings.Index(rest, "."); dot >= 0 {
+		rest = rest[:dot]
+	}
+
+	return full[:slash+1] + rest
+}
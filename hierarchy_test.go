@@ -0,0 +1,86 @@
+package vlog
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetEffectiveLevelConcurrentWithSetLevel(t *testing.T) {
+	lg := GetLogger("race-test-logger", nil)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				lg.SetLevel(INFO)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				lg.GetEffectiveLevel()
+				lg.GetLevel()
+			}
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+}
+
+func TestSetLevelsHierarchyInheritance(t *testing.T) {
+	if err := SetLevels("hier.app=INFO,hier.app.db=DEBUG"); err != nil {
+		t.Fatalf("SetLevels: %v", err)
+	}
+
+	app := GetLogger("hier.app", nil)
+	if got := app.GetEffectiveLevel(); got != INFO {
+		t.Fatalf("hier.app effective level = %v, want INFO", got)
+	}
+
+	db := GetLogger("hier.app.db", nil)
+	if got := db.GetEffectiveLevel(); got != DEBUG {
+		t.Fatalf("hier.app.db effective level = %v, want DEBUG", got)
+	}
+
+	pool := GetLogger("hier.app.db.pool", nil)
+	if got := pool.GetEffectiveLevel(); got != DEBUG {
+		t.Fatalf("hier.app.db.pool effective level = %v, want DEBUG (inherited)", got)
+	}
+}
+
+func TestSetLevelsRejectsWholeSpecOnMalformedEntry(t *testing.T) {
+	before := GetLogger("partial.app", nil).GetEffectiveLevel()
+
+	err := SetLevels("partial.app=INFO,partial.bogus=NOTALEVEL")
+	if err == nil {
+		t.Fatal("SetLevels with a malformed entry should return an error")
+	}
+
+	if got := GetLogger("partial.app", nil).GetEffectiveLevel(); got != before {
+		t.Fatalf("SetLevels applied partial.app=INFO despite returning an error: effective level = %v, want unchanged %v", got, before)
+	}
+}
+
+func TestMustGetPackageLoggerDerivesCallerPackage(t *testing.T) {
+	want := callerPackage(1)
+	lg := MustGetPackageLogger()
+
+	if lg.name != want {
+		t.Fatalf("MustGetPackageLogger logger name = %q, want %q", lg.name, want)
+	}
+}
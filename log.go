@@ -1,15 +1,33 @@
-// Package vlog implements a simple logging package on top of existing Go log package.
-// vlog package adds logging levels and allows changing the logging levels. Otherwise
-// vlog package behaves exactly as the Go log package.
-// vlog does not create any hirarchy of the loggers.
+// Package vlog implements a structured logging package on top of the
+// existing Go log package. vlog package adds logging levels and allows
+// changing the logging levels, and loggers are organized in a dotted-name
+// hierarchy (see SetLevels) that levels can be configured against.
 // Root logger is always created with name "".
+//
+// Log records carry structured key/value fields in addition to their
+// message, and are rendered through a pluggable Formatter (TextFormatter,
+// LogfmtFormatter or JSONFormatter) so vlog output can feed machine
+// consumers without callers changing how they log.
+//
+// Migration note: the level methods (Trace, Debug, Info, Warn, Error,
+// Critical) used to take `v ...interface{}` and Sprint-concatenate their
+// arguments, e.g. Info("user", "alice", "logged in"). They now take
+// `(msg string, kv ...any)`: the first argument is the message and the
+// rest are alternating key/value pairs. Old call sites with more than one
+// argument still compile but change meaning silently - the example above
+// now logs message "user" with field alice="logged in" instead of
+// concatenating all three. Audit any call passing more than a single
+// message argument when upgrading.
 package vlog
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"sync"
+	"time"
 )
 
 type Level int
@@ -25,9 +43,20 @@ const (
 )
 
 type Logger struct {
-	logger *log.Logger
-	name   string
-	level  Level
+	logger   *log.Logger
+	name     string
+	level    Level
+	levelSet bool
+	fields   []any
+
+	fmtMu     sync.RWMutex
+	formatter Formatter
+
+	sinksMu sync.RWMutex
+	sinks   []Sink
+
+	sampMu  sync.RWMutex
+	sampler Sampler
 }
 
 var defaultFlags = log.Ldate | log.Ltime | log.Lmicroseconds | log.Lshortfile
@@ -40,24 +69,33 @@ func init() {
 	loggers = make(map[string]*Logger)
 
 	rootLogger = &Logger{
-		name:   "",
-		level:  TRACE,
-		logger: log.Default(),
+		name:      "",
+		level:     TRACE,
+		levelSet:  true,
+		logger:    log.Default(),
+		formatter: TextFormatter{},
 	}
 
 	rootLogger.logger.SetFlags(defaultFlags)
+	rootLogger.sinks = []Sink{NewWriterSink(rootLogger.logger.Writer(), TRACE, rootLogger.formatter)}
 	loggers[""] = rootLogger
+
+	if spec := os.Getenv("VLOG_LEVELS"); spec != "" {
+		SetLevels(spec)
+	}
 }
 
-func newLogger(name string, out io.Writer, flag int) *Logger {
+func newLogger(name string, out io.Writer, flag int, formatter Formatter) *Logger {
 	if len(name) == 0 {
 		return loggers[""]
 	}
 
 	rootLogger = &Logger{
-		name:   name,
-		level:  TRACE,
-		logger: log.New(out, name+" ", flag),
+		name:      name,
+		level:     effectiveLevelLocked(name),
+		logger:    log.New(out, name+" ", flag),
+		formatter: formatter,
+		sinks:     []Sink{NewWriterSink(out, TRACE, formatter)},
 	}
 	loggers[name] = rootLogger
 
@@ -77,21 +115,29 @@ func GetLogger(name string, parent *Logger) *Logger {
 	}
 
 	if parent != nil {
-		return newLogger(name, parent.logger.Writer(), parent.logger.Flags())
+		return newLogger(name, parent.logger.Writer(), parent.logger.Flags(), parent.GetFormatter())
 	}
 
 	rootLogger := loggers[""]
-	return newLogger(name, rootLogger.logger.Writer(), rootLogger.logger.Flags())
+	return newLogger(name, rootLogger.logger.Writer(), rootLogger.logger.Flags(), rootLogger.GetFormatter())
 }
 
-// SetFlags proxy call to log.Logger SetFlags
+// SetFlags sets the flags on the embedded log.Logger.
+//
+// Deprecated: record rendering goes through Sinks and the active
+// Formatter (see SetFormatter), neither of which consults these flags.
+// SetFlags has no effect on what gets logged; it is kept only so callers
+// written against the pre-Formatter API still build.
 func (l *Logger) SetFlags(flag int) {
 	l.logger.SetFlags(flag)
 }
 
-// SetOutput proxy call to log.Logger SetOutput
+// SetOutput proxy call to log.Logger SetOutput. It also replaces this
+// logger's sinks with a single WriterSink writing to w; use SetSinks for
+// multi-sink fan-out.
 func (l *Logger) SetOutput(w io.Writer) {
 	l.logger.SetOutput(w)
+	l.SetSinks(NewWriterSink(w, TRACE, l.GetFormatter()))
 }
 
 // GetOutput returns the current output destination
@@ -99,101 +145,252 @@ func (l *Logger) GetOutput() io.Writer {
 	return l.logger.Writer()
 }
 
-// SetLevel sets the logging level for the logger.
+// AddSink appends sink to the set of sinks this logger fans records out
+// to.
+func (l *Logger) AddSink(sink Sink) {
+	l.sinksMu.Lock()
+	defer l.sinksMu.Unlock()
+	l.sinks = append(l.sinks, sink)
+}
+
+// SetSinks replaces this logger's sinks wholesale.
+func (l *Logger) SetSinks(sinks ...Sink) {
+	l.sinksMu.Lock()
+	defer l.sinksMu.Unlock()
+	l.sinks = sinks
+}
+
+// Sinks returns the sinks currently attached to this logger.
+func (l *Logger) Sinks() []Sink {
+	l.sinksMu.RLock()
+	defer l.sinksMu.RUnlock()
+	return append([]Sink(nil), l.sinks...)
+}
+
+// Flush waits for every attached sink that buffers records (e.g.
+// AsyncSink) to drain, or for ctx to be done, whichever comes first.
+func (l *Logger) Flush(ctx context.Context) error {
+	for _, sink := range l.Sinks() {
+		if f, ok := sink.(Flusher); ok {
+			if err := f.Flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetLevel sets the logging level for the logger, overriding whatever it
+// would otherwise inherit from its parent in the name hierarchy.
 // All the levels above given level would be emitted.
 func (l *Logger) SetLevel(lvl Level) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	l.level = lvl
+	l.levelSet = true
+	explicitLevels[l.name] = lvl
 }
 
-// GetLevel returns the current logging level
+// GetLevel returns this logger's own level: the one last set via SetLevel
+// or SetLevels, or its initial default if neither ever named it. Use
+// GetEffectiveLevel to resolve the level this logger actually emits at.
 func (l *Logger) GetLevel() Level {
+	mu.Lock()
+	defer mu.Unlock()
 	return l.level
 }
 
-func (l *Logger) output(level string, v ...interface{}) {
-	l.logger.Output(3, fmt.Sprint(append([]interface{}{level}, v...)...))
+// GetEffectiveLevel returns the level this logger actually emits at: its
+// own level if set via SetLevel, otherwise the nearest ancestor's level in
+// the dotted-name hierarchy (see SetLevels), falling back to the root
+// logger's level.
+func (l *Logger) GetEffectiveLevel() Level {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l.levelSet {
+		return l.level
+	}
+	return effectiveLevelLocked(l.name)
+}
+
+// SetFormatter swaps the formatter used to render records for this logger.
+// The swap is atomic with respect to concurrent log calls, and also
+// updates any WriterSink already attached to this logger (e.g. the
+// default sink created by GetLogger/SetOutput) so records rendered
+// through it pick up the new formatter immediately.
+func (l *Logger) SetFormatter(formatter Formatter) {
+	l.fmtMu.Lock()
+	l.formatter = formatter
+	l.fmtMu.Unlock()
+
+	for _, sink := range l.Sinks() {
+		if ws, ok := sink.(*WriterSink); ok {
+			ws.SetFormatter(formatter)
+		}
+	}
+}
+
+// GetFormatter returns the formatter currently used by this logger.
+func (l *Logger) GetFormatter() Formatter {
+	l.fmtMu.RLock()
+	defer l.fmtMu.RUnlock()
+	return l.formatter
+}
+
+// With returns a child logger that writes to the same destination as l but
+// prepends the given key/value pairs to every record it emits. Fields
+// accumulate across nested calls to With.
+func (l *Logger) With(kv ...any) *Logger {
+	fields := make([]any, 0, len(l.fields)+len(kv))
+	fields = append(fields, l.fields...)
+	fields = append(fields, kv...)
+
+	mu.Lock()
+	level, levelSet := l.level, l.levelSet
+	mu.Unlock()
+
+	return &Logger{
+		logger:    l.logger,
+		name:      l.name,
+		level:     level,
+		levelSet:  levelSet,
+		fields:    fields,
+		formatter: l.GetFormatter(),
+		sinks:     l.Sinks(),
+		sampler:   l.GetSampler(),
+	}
+}
+
+// SetSampler attaches a Sampler that gates every subsequent log call on
+// this logger. A nil sampler (the default) disables sampling.
+func (l *Logger) SetSampler(sampler Sampler) {
+	l.sampMu.Lock()
+	defer l.sampMu.Unlock()
+	l.sampler = sampler
+}
+
+// GetSampler returns the sampler currently attached to this logger, or
+// nil if none is set.
+func (l *Logger) GetSampler() Sampler {
+	l.sampMu.RLock()
+	defer l.sampMu.RUnlock()
+	return l.sampler
+}
+
+// sample consults the attached sampler, if any, for (level, key),
+// emitting a synthetic "dropped" record first if the sampler reports
+// occurrences dropped since the last report. It returns whether the
+// caller should proceed to log.
+func (l *Logger) sample(level Level, key string) bool {
+	sampler := l.GetSampler()
+	if sampler == nil {
+		return true
+	}
+
+	decision := sampler.Sample(level, key)
+	if decision.Dropped > 0 {
+		l.output(level, "dropped", []any{"dropped", decision.Dropped, "key", key})
+	}
+
+	return decision.Allow
+}
+
+func (l *Logger) output(level Level, msg string, kv []any) {
+	fields := make([]any, 0, len(l.fields)+len(kv))
+	fields = append(fields, l.fields...)
+	fields = append(fields, kv...)
+
+	rec := Record{Level: level, Time: time.Now(), Logger: l.name, Msg: msg, Fields: fields}
+
+	for _, sink := range l.Sinks() {
+		if rec.Level >= sink.Level() {
+			sink.Write(rec)
+		}
+	}
 }
 
-// Trace emits a log at trace logging level
-func (l *Logger) Trace(v ...interface{}) {
-	if TRACE >= l.level {
-		l.output("trace ", v...)
+// Trace emits a structured log at trace logging level
+func (l *Logger) Trace(msg string, kv ...any) {
+	if TRACE >= l.GetEffectiveLevel() && l.sample(TRACE, msg) {
+		l.output(TRACE, msg, kv)
 	}
 }
 
-// Debug emits a log at debug logging level
-func (l *Logger) Debug(v ...interface{}) {
-	if DEBUG >= l.level {
-		l.output("debug ", v...)
+// Debug emits a structured log at debug logging level
+func (l *Logger) Debug(msg string, kv ...any) {
+	if DEBUG >= l.GetEffectiveLevel() && l.sample(DEBUG, msg) {
+		l.output(DEBUG, msg, kv)
 	}
 }
 
-// Info emits a log at info logging level
-func (l *Logger) Info(v ...interface{}) {
-	if INFO >= l.level {
-		l.output("info ", v...)
+// Info emits a structured log at info logging level
+func (l *Logger) Info(msg string, kv ...any) {
+	if INFO >= l.GetEffectiveLevel() && l.sample(INFO, msg) {
+		l.output(INFO, msg, kv)
 	}
 }
 
-// Warn emits a log at warn logging level
-func (l *Logger) Warn(v ...interface{}) {
-	if WARN >= l.level {
-		l.output("warn ", v...)
+// Warn emits a structured log at warn logging level
+func (l *Logger) Warn(msg string, kv ...any) {
+	if WARN >= l.GetEffectiveLevel() && l.sample(WARN, msg) {
+		l.output(WARN, msg, kv)
 	}
 }
 
-// Error emits a log at error logging level
-func (l *Logger) Error(v ...interface{}) {
-	if ERROR >= l.level {
-		l.output("error ", v...)
+// Error emits a structured log at error logging level
+func (l *Logger) Error(msg string, kv ...any) {
+	if ERROR >= l.GetEffectiveLevel() && l.sample(ERROR, msg) {
+		l.output(ERROR, msg, kv)
 	}
 }
 
-// Critical emits a log at critical logging level
-func (l *Logger) Critical(v ...interface{}) {
-	if CRITICAL >= l.level {
-		l.output("critical ", v...)
+// Critical emits a structured log at critical logging level
+func (l *Logger) Critical(msg string, kv ...any) {
+	if CRITICAL >= l.GetEffectiveLevel() && l.sample(CRITICAL, msg) {
+		l.output(CRITICAL, msg, kv)
 	}
 }
 
 // Tracef emits a log at tracef logging level with specified format string
 func (l *Logger) Tracef(format string, v ...interface{}) {
-	if TRACE >= l.level {
-		l.output("trace ", fmt.Sprintf(format, v...))
+	if TRACE >= l.GetEffectiveLevel() && l.sample(TRACE, format) {
+		l.output(TRACE, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Debugf emits a log at debugf logging level with specified format string
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if DEBUG >= l.level {
-		l.output("debug ", fmt.Sprintf(format, v...))
+	if DEBUG >= l.GetEffectiveLevel() && l.sample(DEBUG, format) {
+		l.output(DEBUG, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Infof emits a log at infof logging level with specified format string
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if INFO >= l.level {
-		l.output("info ", fmt.Sprintf(format, v...))
+	if INFO >= l.GetEffectiveLevel() && l.sample(INFO, format) {
+		l.output(INFO, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Warnf emits a log at warnf logging level with specified format string
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	if WARN >= l.level {
-		l.output("warn ", fmt.Sprintf(format, v...))
+	if WARN >= l.GetEffectiveLevel() && l.sample(WARN, format) {
+		l.output(WARN, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Errorf emits a log at errorf logging level with specified format string
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if ERROR >= l.level {
-		l.output("error ", fmt.Sprintf(format, v...))
+	if ERROR >= l.GetEffectiveLevel() && l.sample(ERROR, format) {
+		l.output(ERROR, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Criticalf emits a log at criticalf logging level with specified format string
 func (l *Logger) Criticalf(format string, v ...interface{}) {
-	if CRITICAL >= l.level {
-		l.output("critical ", fmt.Sprintf(format, v...))
+	if CRITICAL >= l.GetEffectiveLevel() && l.sample(CRITICAL, format) {
+		l.output(CRITICAL, fmt.Sprintf(format, v...), nil)
 	}
 }
@@ -0,0 +1,89 @@
+package vlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSetFormatterAffectsSubsequentWrites(t *testing.T) {
+	buf := new(bytes.Buffer)
+	lg := GetLogger("set-formatter-test-logger", nil)
+	lg.SetOutput(buf)
+
+	lg.Info("before")
+	if strings.Contains(buf.String(), `"msg"`) {
+		t.Fatalf("logger emitted JSON before SetFormatter was called: %q", buf.String())
+	}
+
+	lg.SetFormatter(JSONFormatter{})
+	buf.Reset()
+	lg.Info("after")
+
+	if !strings.Contains(buf.String(), `"msg":"after"`) {
+		t.Fatalf("SetFormatter did not take effect on the logger's existing sink, got %q", buf.String())
+	}
+}
+
+func TestWithPrependsFieldsAndInheritsOutput(t *testing.T) {
+	buf := new(bytes.Buffer)
+	lg := GetLogger("with-test-logger", nil)
+	lg.SetOutput(buf)
+
+	child := lg.With("request_id", "abc123")
+	child.Info("handled request", "status", 200)
+
+	out := buf.String()
+	if !strings.Contains(out, "request_id=abc123") {
+		t.Fatalf("With did not prepend its fields, got %q", out)
+	}
+	if !strings.Contains(out, "status=200") {
+		t.Fatalf("With dropped the call's own fields, got %q", out)
+	}
+}
+
+func TestAddSinkFansOutToEverySink(t *testing.T) {
+	lg := GetLogger("add-sink-test-logger", nil)
+	lg.SetOutput(new(bytes.Buffer))
+
+	extra := &recordingSink{}
+	lg.AddSink(extra)
+	lg.Info("fan out")
+
+	if extra.len() != 1 {
+		t.Fatalf("extra sink got %d records, want 1", extra.len())
+	}
+}
+
+func TestSetSinksReplacesExistingSinks(t *testing.T) {
+	lg := GetLogger("set-sinks-test-logger", nil)
+	lg.SetOutput(new(bytes.Buffer))
+
+	only := &recordingSink{}
+	lg.SetSinks(only)
+	lg.Info("replaced")
+
+	if only.len() != 1 {
+		t.Fatalf("replacement sink got %d records, want 1", only.len())
+	}
+	if got := len(lg.Sinks()); got != 1 {
+		t.Fatalf("len(Sinks()) = %d, want 1", got)
+	}
+}
+
+func TestFlushDrainsAsyncSinks(t *testing.T) {
+	next := &recordingSink{}
+	async := NewAsyncSink(next, 8, Block)
+
+	lg := GetLogger("flush-test-logger", nil)
+	lg.SetSinks(async)
+	lg.Info("buffered")
+
+	if err := lg.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if next.len() != 1 {
+		t.Fatalf("wrapped sink got %d records after Flush, want 1", next.len())
+	}
+}
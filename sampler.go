@@ -0,0 +1,145 @@
+package vlog
+
+import (
+	"sync"
+	"time"
+)
+
+// SampleDecision is the result of consulting a Sampler for a single log
+// call. Dropped, when non-zero, is the number of prior occurrences of the
+// same (level, key) that were suppressed since the last time a decision
+// reported a non-zero Dropped; the caller emits it as a synthetic
+// "dropped" record so sampling stays observable.
+type SampleDecision struct {
+	Allow   bool
+	Dropped int
+}
+
+// Sampler decides whether a log call identified by (level, key) should be
+// emitted. key is the call's message, or its format string for the *f
+// methods, so e.g. Debugf("row %d processed", i) collapses to one entry
+// regardless of i.
+type Sampler interface {
+	Sample(level Level, key string) SampleDecision
+}
+
+type sampleKey struct {
+	level Level
+	key   string
+}
+
+// CountSampler emits the first n occurrences of a (level, key) in each
+// interval, then every m-th occurrence after that.
+type CountSampler struct {
+	mu         sync.Mutex
+	first      int
+	thereafter int
+	interval   time.Duration
+	state      map[sampleKey]*countState
+}
+
+type countState struct {
+	windowStart time.Time
+	count       int
+	dropped     int
+}
+
+// NewCountSampler returns a CountSampler that allows the first occurrences
+// of each (level, key) in every interval window, then every thereafter-th
+// occurrence after that. thereafter <= 0 suppresses everything past
+// first.
+func NewCountSampler(first, thereafter int, interval time.Duration) *CountSampler {
+	return &CountSampler{
+		first:      first,
+		thereafter: thereafter,
+		interval:   interval,
+		state:      make(map[sampleKey]*countState),
+	}
+}
+
+func (s *CountSampler) Sample(level Level, key string) SampleDecision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := sampleKey{level, key}
+	now := time.Now()
+
+	st, ok := s.state[k]
+	if !ok || now.Sub(st.windowStart) >= s.interval {
+		dropped := 0
+		if ok {
+			dropped = st.dropped
+		}
+		st = &countState{windowStart: now, count: 1}
+		s.state[k] = st
+		return SampleDecision{Allow: true, Dropped: dropped}
+	}
+
+	st.count++
+	if st.count <= s.first {
+		return SampleDecision{Allow: true}
+	}
+
+	if s.thereafter > 0 && (st.count-s.first)%s.thereafter == 0 {
+		dropped := st.dropped
+		st.dropped = 0
+		return SampleDecision{Allow: true, Dropped: dropped}
+	}
+
+	st.dropped++
+	return SampleDecision{Allow: false}
+}
+
+// RateSampler allows up to perSecond occurrences of each (level, key) per
+// second, using a token bucket so brief bursts are absorbed.
+type RateSampler struct {
+	mu      sync.Mutex
+	perSec  float64
+	buckets map[sampleKey]*rateState
+}
+
+type rateState struct {
+	tokens  float64
+	last    time.Time
+	dropped int
+}
+
+// NewRateSampler returns a RateSampler allowing perSecond occurrences of
+// each (level, key) per second.
+func NewRateSampler(perSecond int) *RateSampler {
+	return &RateSampler{
+		perSec:  float64(perSecond),
+		buckets: make(map[sampleKey]*rateState),
+	}
+}
+
+func (s *RateSampler) Sample(level Level, key string) SampleDecision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := sampleKey{level, key}
+	now := time.Now()
+
+	st, ok := s.buckets[k]
+	if !ok {
+		st = &rateState{tokens: s.perSec, last: now}
+		s.buckets[k] = st
+	} else {
+		elapsed := now.Sub(st.last).Seconds()
+		st.tokens += elapsed * s.perSec
+		if st.tokens > s.perSec {
+			st.tokens = s.perSec
+		}
+		st.last = now
+	}
+
+	if st.tokens >= 1 {
+		st.tokens--
+		dropped := st.dropped
+		st.dropped = 0
+		return SampleDecision{Allow: true, Dropped: dropped}
+	}
+
+	st.dropped++
+	return SampleDecision{Allow: false}
+}
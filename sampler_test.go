@@ -0,0 +1,78 @@
+package vlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountSamplerAllowsFirstThenThereafter(t *testing.T) {
+	s := NewCountSampler(2, 3, time.Hour)
+
+	var allowed, dropped int
+	for i := 0; i < 10; i++ {
+		d := s.Sample(INFO, "row processed")
+		if d.Allow {
+			allowed++
+		}
+		dropped += d.Dropped
+	}
+
+	// first=2 allowed outright (occurrences 1-2), then every 3rd of the
+	// remaining 8 (occurrences 3-10) is allowed: occurrences 5 and 8 (the
+	// 3rd and 6th after first). That's 2 + 2 = 4 allowed total.
+	if allowed != 4 {
+		t.Fatalf("allowed = %d, want 4", allowed)
+	}
+}
+
+func TestCountSamplerResetsPerInterval(t *testing.T) {
+	s := NewCountSampler(1, 0, 50*time.Millisecond)
+
+	d1 := s.Sample(INFO, "k")
+	if !d1.Allow {
+		t.Fatal("first occurrence should be allowed")
+	}
+
+	d2 := s.Sample(INFO, "k")
+	if d2.Allow {
+		t.Fatal("second occurrence within the same window should be dropped")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	d3 := s.Sample(INFO, "k")
+	if !d3.Allow {
+		t.Fatal("first occurrence of a new window should be allowed")
+	}
+	if d3.Dropped != 1 {
+		t.Fatalf("Dropped = %d, want 1 (the suppressed occurrence from the prior window)", d3.Dropped)
+	}
+}
+
+func TestCountSamplerKeysByLevelAndMessage(t *testing.T) {
+	s := NewCountSampler(1, 0, time.Hour)
+
+	if !s.Sample(INFO, "a").Allow {
+		t.Fatal("first occurrence of (INFO, a) should be allowed")
+	}
+	if !s.Sample(WARN, "a").Allow {
+		t.Fatal("first occurrence of (WARN, a) should be allowed even though (INFO, a) was already seen")
+	}
+	if !s.Sample(INFO, "b").Allow {
+		t.Fatal("first occurrence of (INFO, b) should be allowed even though (INFO, a) was already seen")
+	}
+}
+
+func TestRateSamplerAllowsWithinBudget(t *testing.T) {
+	s := NewRateSampler(2)
+
+	if !s.Sample(DEBUG, "k").Allow {
+		t.Fatal("first token should be available")
+	}
+	if !s.Sample(DEBUG, "k").Allow {
+		t.Fatal("second token should be available")
+	}
+	if s.Sample(DEBUG, "k").Allow {
+		t.Fatal("third call should exceed the 2/sec budget")
+	}
+}
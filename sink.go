@@ -0,0 +1,199 @@
+package vlog
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Record is a single log event handed to a Sink.
+type Record struct {
+	Level  Level
+	Time   time.Time
+	Logger string
+	Msg    string
+	Fields []any
+}
+
+// Sink receives log records and is responsible for rendering and
+// persisting them. A Logger may fan a record out to several sinks, each
+// filtering by its own minimum level.
+type Sink interface {
+	// Write renders and persists rec. The Logger only calls Write for
+	// records at or above Level().
+	Write(rec Record) error
+	// Level reports the minimum level this sink accepts.
+	Level() Level
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// Flusher is implemented by sinks that buffer records, e.g. AsyncSink.
+// Logger.Flush calls Flush on every sink that implements it.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// WriterSink renders records with a Formatter and writes them to an
+// io.Writer, e.g. os.Stderr or a network connection.
+type WriterSink struct {
+	mu        sync.Mutex
+	w         io.Writer
+	level     Level
+	formatter Formatter
+}
+
+// NewWriterSink returns a Sink that writes records at or above level to w,
+// rendered with formatter. A nil formatter defaults to TextFormatter.
+func NewWriterSink(w io.Writer, level Level, formatter Formatter) *WriterSink {
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	return &WriterSink{w: w, level: level, formatter: formatter}
+}
+
+func (s *WriterSink) Write(rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(s.formatter.Format(rec.Level, rec.Time, rec.Logger, rec.Msg, rec.Fields))
+	return err
+}
+
+// SetFormatter swaps the formatter used to render subsequent records. A nil
+// formatter defaults to TextFormatter. Logger.SetFormatter calls this on
+// any WriterSink it manages so the swap is visible immediately, without
+// requiring the caller to reattach the sink.
+func (s *WriterSink) SetFormatter(formatter Formatter) {
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.formatter = formatter
+}
+
+// Level returns the minimum level this sink accepts.
+func (s *WriterSink) Level() Level { return s.level }
+
+// Close closes the underlying writer if it implements io.Closer.
+func (s *WriterSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// OverflowPolicy controls what AsyncSink does when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest buffered record to make room.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards the record that just arrived.
+	DropNewest
+	// Block waits until the buffer has room, applying backpressure.
+	Block
+)
+
+// AsyncSink wraps another Sink so records are handed off to a bounded
+// buffer and drained by a background goroutine, keeping callers off the
+// hot path of slow sinks such as network writers.
+type AsyncSink struct {
+	next     Sink
+	overflow OverflowPolicy
+	queue    chan Record
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// NewAsyncSink starts a background goroutine draining into next and
+// returns a Sink that buffers up to size records before overflow applies.
+func NewAsyncSink(next Sink, size int, overflow OverflowPolicy) *AsyncSink {
+	a := &AsyncSink{
+		next:     next,
+		overflow: overflow,
+		queue:    make(chan Record, size),
+		done:     make(chan struct{}),
+	}
+
+	a.wg.Add(1)
+	go a.run()
+
+	return a
+}
+
+func (a *AsyncSink) run() {
+	defer a.wg.Done()
+
+	for {
+		select {
+		case rec := <-a.queue:
+			a.next.Write(rec)
+		case <-a.done:
+			for {
+				select {
+				case rec := <-a.queue:
+					a.next.Write(rec)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *AsyncSink) Write(rec Record) error {
+	select {
+	case a.queue <- rec:
+		return nil
+	default:
+	}
+
+	switch a.overflow {
+	case Block:
+		select {
+		case a.queue <- rec:
+		case <-a.done:
+			// Close has already started draining; don't block forever
+			// waiting for room that will never free up.
+		}
+	case DropNewest:
+		// drop rec
+	case DropOldest:
+		select {
+		case <-a.queue:
+		default:
+		}
+		select {
+		case a.queue <- rec:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Level returns the minimum level of the wrapped sink.
+func (a *AsyncSink) Level() Level { return a.next.Level() }
+
+// Flush blocks until the buffer has drained or ctx is done.
+func (a *AsyncSink) Flush(ctx context.Context) error {
+	for len(a.queue) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// Close stops the background goroutine after draining any buffered
+// records, then closes the wrapped sink.
+func (a *AsyncSink) Close() error {
+	a.once.Do(func() { close(a.done) })
+	a.wg.Wait()
+	return a.next.Close()
+}
@@ -0,0 +1,118 @@
+package vlog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// RotatingFileSink writes records to a file, rotating it once it exceeds
+// maxSize bytes or maxAge since it was opened, whichever comes first. The
+// rotated-out file is renamed with a timestamp suffix; a maxAge or maxSize
+// of zero disables that trigger.
+type RotatingFileSink struct {
+	mu        sync.Mutex
+	path      string
+	maxSize   int64
+	maxAge    time.Duration
+	level     Level
+	formatter Formatter
+
+	f        *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewRotatingFileSink opens (or creates) path for appending and returns a
+// Sink that rotates it per maxSize/maxAge. A nil formatter defaults to
+// TextFormatter.
+func NewRotatingFileSink(path string, maxSize int64, maxAge time.Duration, level Level, formatter Formatter) (*RotatingFileSink, error) {
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+
+	s := &RotatingFileSink{
+		path:      path,
+		maxSize:   maxSize,
+		maxAge:    maxAge,
+		level:     level,
+		formatter: formatter,
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *RotatingFileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	s.f = f
+	s.size = info.Size()
+	s.openedAt = time.Now()
+
+	return nil
+}
+
+func (s *RotatingFileSink) needsRotation(next int64) bool {
+	if s.maxSize > 0 && s.size+next > s.maxSize {
+		return true
+	}
+	if s.maxAge > 0 && time.Since(s.openedAt) > s.maxAge {
+		return true
+	}
+	return false
+}
+
+func (s *RotatingFileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+
+	rotated := fmt.Sprintf("%s.%s", s.path, time.Now().Format("20060102T150405.000000"))
+	if err := os.Rename(s.path, rotated); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+func (s *RotatingFileSink) Write(rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := s.formatter.Format(rec.Level, rec.Time, rec.Logger, rec.Msg, rec.Fields)
+
+	if s.needsRotation(int64(len(line))) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.f.Write(line)
+	s.size += int64(n)
+
+	return err
+}
+
+// Level returns the minimum level this sink accepts.
+func (s *RotatingFileSink) Level() Level { return s.level }
+
+// Close closes the current file.
+func (s *RotatingFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
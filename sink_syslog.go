@@ -0,0 +1,52 @@
+//go:build !windows
+
+package vlog
+
+import "log/syslog"
+
+// SyslogSink renders records with a Formatter and writes them to syslog at
+// a severity derived from the record's Level.
+type SyslogSink struct {
+	w         *syslog.Writer
+	level     Level
+	formatter Formatter
+}
+
+// NewSyslogSink dials the syslog daemon described by network/raddr (see
+// syslog.Dial; network == "" dials the local syslog) and returns a Sink
+// writing at or above level. A nil formatter defaults to TextFormatter.
+func NewSyslogSink(network, raddr string, priority syslog.Priority, tag string, level Level, formatter Formatter) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, raddr, priority, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+
+	return &SyslogSink{w: w, level: level, formatter: formatter}, nil
+}
+
+func (s *SyslogSink) Write(rec Record) error {
+	line := string(s.formatter.Format(rec.Level, rec.Time, rec.Logger, rec.Msg, rec.Fields))
+
+	switch {
+	case rec.Level >= CRITICAL:
+		return s.w.Crit(line)
+	case rec.Level >= ERROR:
+		return s.w.Err(line)
+	case rec.Level >= WARN:
+		return s.w.Warning(line)
+	case rec.Level >= INFO:
+		return s.w.Info(line)
+	default:
+		return s.w.Debug(line)
+	}
+}
+
+// Level returns the minimum level this sink accepts.
+func (s *SyslogSink) Level() Level { return s.level }
+
+// Close closes the connection to the syslog daemon.
+func (s *SyslogSink) Close() error { return s.w.Close() }
@@ -0,0 +1,66 @@
+package vlog
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (s *recordingSink) Write(rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, rec)
+	return nil
+}
+
+func (s *recordingSink) Level() Level { return TRACE }
+func (s *recordingSink) Close() error { return nil }
+
+func (s *recordingSink) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+func TestAsyncSinkDropOldest(t *testing.T) {
+	next := &recordingSink{}
+	a := NewAsyncSink(next, 1, DropOldest)
+	defer a.Close()
+
+	a.Write(Record{Msg: "first"})
+	a.Write(Record{Msg: "second"})
+
+	if err := a.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+}
+
+func TestAsyncSinkWriteAfterCloseDoesNotBlock(t *testing.T) {
+	next := &recordingSink{}
+	a := NewAsyncSink(next, 1, Block)
+
+	// Close drains this record. After Close, the first Write fills the
+	// buffer, so the second would otherwise have to wait for a reader
+	// that no longer exists.
+	a.Write(Record{Msg: "fills-the-buffer"})
+	a.Close()
+
+	done := make(chan struct{})
+	go func() {
+		a.Write(Record{Msg: "after-close-1"})
+		a.Write(Record{Msg: "after-close-2"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Write after Close blocked forever with Block overflow policy")
+	}
+}